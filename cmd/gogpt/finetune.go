@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/finetune"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// finetuneEventPollInterval is how often "finetune events -follow" polls
+// OpenAI for new events.
+const finetuneEventPollInterval = 5 * time.Second
+
+// runFinetune dispatches "gogpt finetune <subcommand>" to its handler.
+func runFinetune(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: gogpt finetune <create|list|get|cancel|events> [options]")
+		return
+	}
+
+	configDir, err := config.Dir()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	cfg, err := config.Load(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	db, err := store.Open(filepath.Join(configDir, "qa.db"))
+	if err != nil {
+		log.Fatalf("Error opening BoltDB: %v", err)
+	}
+	defer db.Close()
+
+	bc := cfg.Backend("openai")
+	client := finetune.NewClient(cfg.APIKey, bc.Endpoint)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		finetuneCreate(ctx, client, db, args[1:])
+	case "list":
+		finetuneList(ctx, client)
+	case "get":
+		finetuneGet(ctx, client, args[1:])
+	case "cancel":
+		finetuneCancel(ctx, client, args[1:])
+	case "events":
+		finetuneEvents(ctx, client, args[1:])
+	default:
+		log.Fatalf("Unknown finetune subcommand %q", args[0])
+	}
+}
+
+func finetuneCreate(ctx context.Context, client *finetune.Client, db *bolt.DB, args []string) {
+	fs := flag.NewFlagSet("finetune create", flag.ExitOnError)
+	trainingFile := fs.String("training-file", "", "Path to the local training file to upload")
+	validationFile := fs.String("validation-file", "", "Path to the local validation file to upload")
+	model := fs.String("model", "gpt-3.5-turbo", "Base model to fine-tune")
+	nEpochs := fs.Int("n-epochs", 0, "Number of training epochs (0 = auto)")
+	batchSize := fs.Int("batch-size", 0, "Batch size (0 = auto)")
+	learningRateMultiplier := fs.Float64("learning-rate-multiplier", 0, "Learning rate multiplier (0 = auto)")
+	fs.Parse(args)
+
+	if *trainingFile == "" {
+		log.Fatalf("-training-file is required")
+	}
+
+	trainingFileID, err := client.UploadFile(ctx, *trainingFile, "fine-tune")
+	if err != nil {
+		log.Fatalf("Error uploading training file: %v", err)
+	}
+
+	params := finetune.CreateJobParams{TrainingFile: trainingFileID, Model: *model}
+
+	if *validationFile != "" {
+		validationFileID, err := client.UploadFile(ctx, *validationFile, "fine-tune")
+		if err != nil {
+			log.Fatalf("Error uploading validation file: %v", err)
+		}
+
+		params.ValidationFile = validationFileID
+	}
+
+	if *nEpochs > 0 {
+		params.Hyperparameters.NEpochs = *nEpochs
+	}
+	if *batchSize > 0 {
+		params.Hyperparameters.BatchSize = *batchSize
+	}
+	if *learningRateMultiplier > 0 {
+		params.Hyperparameters.LearningRateMultiplier = *learningRateMultiplier
+	}
+
+	job, err := client.CreateJob(ctx, params)
+	if err != nil {
+		log.Fatalf("Error creating fine-tuning job: %v", err)
+	}
+
+	err = store.StoreFinetuneJob(db, store.FinetuneJob{
+		ID:        job.ID,
+		Model:     job.Model,
+		Status:    job.Status,
+		CreatedAt: job.CreatedAt,
+	})
+	if err != nil {
+		log.Fatalf("Error storing fine-tuning job: %v", err)
+	}
+
+	fmt.Printf("Created fine-tuning job %s (status: %s)\n", job.ID, job.Status)
+}
+
+func finetuneList(ctx context.Context, client *finetune.Client) {
+	jobs, err := client.ListJobs(ctx)
+	if err != nil {
+		log.Fatalf("Error listing fine-tuning jobs: %v", err)
+	}
+
+	for _, job := range jobs {
+		fmt.Printf("%s\t%s\t%s\n", job.ID, job.Model, job.Status)
+	}
+}
+
+func finetuneGet(ctx context.Context, client *finetune.Client, args []string) {
+	fs := flag.NewFlagSet("finetune get", flag.ExitOnError)
+	id := fs.String("id", "", "Fine-tuning job ID")
+	fs.Parse(args)
+
+	if *id == "" {
+		log.Fatalf("-id is required")
+	}
+
+	job, err := client.GetJob(ctx, *id)
+	if err != nil {
+		log.Fatalf("Error getting fine-tuning job: %v", err)
+	}
+
+	fmt.Printf("%s\t%s\t%s\t%s\n", job.ID, job.Model, job.Status, job.FineTunedModel)
+}
+
+func finetuneCancel(ctx context.Context, client *finetune.Client, args []string) {
+	fs := flag.NewFlagSet("finetune cancel", flag.ExitOnError)
+	id := fs.String("id", "", "Fine-tuning job ID")
+	fs.Parse(args)
+
+	if *id == "" {
+		log.Fatalf("-id is required")
+	}
+
+	job, err := client.CancelJob(ctx, *id)
+	if err != nil {
+		log.Fatalf("Error cancelling fine-tuning job: %v", err)
+	}
+
+	fmt.Printf("Cancelled fine-tuning job %s (status: %s)\n", job.ID, job.Status)
+}
+
+func finetuneEvents(ctx context.Context, client *finetune.Client, args []string) {
+	fs := flag.NewFlagSet("finetune events", flag.ExitOnError)
+	id := fs.String("id", "", "Fine-tuning job ID")
+	follow := fs.Bool("follow", false, "Poll for new events until the job reaches a terminal state")
+	fs.Parse(args)
+
+	if *id == "" {
+		log.Fatalf("-id is required")
+	}
+
+	if !*follow {
+		events, err := client.ListEvents(ctx, *id)
+		if err != nil {
+			log.Fatalf("Error listing fine-tuning events: %v", err)
+		}
+
+		for i := len(events) - 1; i >= 0; i-- {
+			fmt.Printf("[%s] %s\n", events[i].Level, events[i].Message)
+		}
+
+		return
+	}
+
+	_, err := client.WatchEvents(ctx, *id, finetuneEventPollInterval, func(e finetune.Event) {
+		fmt.Printf("[%s] %s\n", e.Level, e.Message)
+	})
+	if err != nil {
+		log.Fatalf("Error watching fine-tuning events: %v", err)
+	}
+}
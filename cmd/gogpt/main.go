@@ -0,0 +1,276 @@
+// Command gogpt asks a question, optionally about a text file, and
+// stores the question/answer pair locally.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jempe/gogpt/internal/audio"
+	"github.com/jempe/gogpt/internal/backend"
+	"github.com/jempe/gogpt/internal/backend/local"
+	"github.com/jempe/gogpt/internal/backend/openai"
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/embeddings"
+	"github.com/jempe/gogpt/internal/modelconfig"
+	"github.com/jempe/gogpt/internal/rag"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+const defaultSystemPrompt = "You are a helpful assistant and you give answers in a list. People generally ask about text and books."
+
+var showHelp = flag.Bool("help", false, "Show help")
+var question = flag.String("question", "", "Question to ask")
+var example_prompt = flag.String("example_prompt", "", "Example prompt")
+var example_response = flag.String("example_response", "", "Example response")
+var file_to_analyze = flag.String("file_to_analyze", "", "Text File to analyze")
+var backendName = flag.String("backend", "openai", "Backend to use: openai or local")
+var modelName = flag.String("model", "", "Named model config from ~/.gogpt/models to use (its backend, if set, overrides -backend)")
+var streamFlag = flag.Bool("stream", false, "Stream the answer to stdout as it's generated")
+var ragK = flag.Int("rag-k", 0, "Number of prior Q&As to retrieve and inject as context (0 = disabled)")
+var ragMinScore = flag.Float64("rag-min-score", 0, "Minimum cosine similarity score for a prior Q&A to be retrieved")
+var speakFlag = flag.Bool("speak", false, "Speak the answer to an audio file next to -file_to_analyze")
+var voice = flag.String("voice", audio.DefaultVoice, "TTS voice to use with -speak")
+var speechFormat = flag.String("speech-format", audio.DefaultFormat, "TTS output audio format to use with -speak")
+var debugMode = flag.Bool("debug", false, "Print all Debug messages")
+
+var logError *log.Logger
+var logInfo *log.Logger
+var logDebug *log.Logger
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "finetune" {
+		runFinetune(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		runReindex()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
+	flag.Parse()
+
+	logError = log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile)
+	logInfo = log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime)
+
+	if *debugMode {
+		logDebug = log.New(os.Stdout, "DEBUG\t", log.Ldate|log.Ltime)
+	} else {
+		logDebug = log.New(ioutil.Discard, "", 0)
+	}
+
+	if flag.NFlag() == 0 || *showHelp {
+		flag.PrintDefaults()
+		os.Exit(0)
+	}
+
+	configDir, err := config.Dir()
+	if err != nil {
+		logError.Printf("%v", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		logError.Printf("%v", err)
+		os.Exit(1)
+	}
+
+	var textToAnalyze []byte
+
+	audioClient := audio.NewClient(cfg.APIKey, "", "")
+
+	if *file_to_analyze != "" {
+		if _, err := os.Stat(*file_to_analyze); os.IsNotExist(err) {
+			logError.Printf("File %s does not exist", *file_to_analyze)
+			os.Exit(1)
+		}
+
+		if audio.IsAudioFile(*file_to_analyze) {
+			transcript, err := audioClient.Transcribe(context.Background(), *file_to_analyze)
+			if err != nil {
+				logError.Printf("Error transcribing file: %v", err)
+				os.Exit(1)
+			}
+
+			textToAnalyze = []byte(transcript)
+		} else {
+			textToAnalyze, err = ioutil.ReadFile(*file_to_analyze)
+			if err != nil {
+				logError.Printf("Error reading file: %v", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	var mc *modelconfig.ModelConfig
+
+	if *modelName != "" {
+		loader := modelconfig.NewLoader(filepath.Join(configDir, "models"))
+		if err := loader.LoadAll(); err != nil {
+			log.Fatalf("Error loading model configs: %v", err)
+		}
+
+		var ok bool
+		mc, ok = loader.Get(*modelName)
+		if !ok {
+			log.Fatalf("Model %q not found in %s", *modelName, loader.Dir)
+		}
+	}
+
+	selectedBackend := *backendName
+	if mc != nil && mc.Backend != "" {
+		selectedBackend = mc.Backend
+	}
+
+	b, bc, err := newBackend(cfg, selectedBackend)
+	if err != nil {
+		log.Fatalf("Error creating backend: %v", err)
+	}
+
+	if mc != nil {
+		if mc.ModelID != "" {
+			bc.Model = mc.ModelID
+		}
+		if mc.Temperature != nil {
+			bc.Temperature = mc.Temperature
+		}
+	}
+
+	db, err := store.Open(filepath.Join(configDir, "qa.db"))
+	if err != nil {
+		log.Fatalf("Error opening BoltDB: %v", err)
+	}
+	defer db.Close()
+
+	embedClient := embeddings.NewClient(cfg.APIKey, "")
+	embedModel := cfg.EmbeddingModelOrDefault()
+
+	var priorQAs []store.QA
+	var questionVector []float64
+
+	if *ragK > 0 {
+		retriever := rag.NewRetriever(db, func(ctx context.Context, text string) ([]float64, error) {
+			return embedClient.Embed(ctx, embedModel, text)
+		})
+
+		priorQAs, questionVector, err = retriever.Retrieve(context.Background(), *question, *ragK, *ragMinScore)
+		if err != nil {
+			log.Fatalf("Error retrieving prior Q&As: %v", err)
+		}
+	}
+
+	answer, err := getAnswer(b, bc, mc, *question, *example_prompt, *example_response, string(textToAnalyze), priorQAs, *streamFlag)
+	if err != nil {
+		log.Fatalf("Error getting answer: %v", err)
+	}
+
+	if err := store.StoreQA(db, *question, answer); err != nil {
+		log.Fatalf("Error storing question and answer: %v", err)
+	}
+
+	if questionVector == nil {
+		questionVector, err = embedClient.Embed(context.Background(), embedModel, *question)
+	}
+	if err != nil {
+		logError.Printf("Error embedding question, skipping RAG indexing: %v", err)
+	} else if err := store.StoreEmbedding(db, *question, questionVector); err != nil {
+		logError.Printf("Error storing embedding: %v", err)
+	}
+
+	if *streamFlag {
+		fmt.Println()
+	} else {
+		fmt.Printf("Answer: %s\n", answer)
+	}
+
+	if *speakFlag {
+		if *file_to_analyze == "" {
+			log.Fatalf("-speak requires -file_to_analyze so the output file has somewhere to go")
+		}
+
+		speech, err := audioClient.Speech(context.Background(), answer, *voice, *speechFormat)
+		if err != nil {
+			log.Fatalf("Error generating speech: %v", err)
+		}
+
+		outputPath := audio.SpeechOutputPath(*file_to_analyze, *speechFormat)
+		if err := ioutil.WriteFile(outputPath, speech, 0644); err != nil {
+			log.Fatalf("Error writing speech file: %v", err)
+		}
+
+		fmt.Printf("Wrote speech to %s\n", outputPath)
+	}
+}
+
+// newBackend builds the backend.Backend selected by name out of the
+// user's config.
+func newBackend(cfg *config.Config, name string) (backend.Backend, config.BackendConfig, error) {
+	bc := cfg.Backend(name)
+
+	switch name {
+	case "openai":
+		return openai.New(cfg.APIKey, bc.Endpoint), bc, nil
+	case "local":
+		return local.New(bc.Endpoint), bc, nil
+	default:
+		return nil, bc, fmt.Errorf("unknown backend %q", name)
+	}
+}
+
+func getAnswer(b backend.Backend, bc config.BackendConfig, mc *modelconfig.ModelConfig, question, examplePrompt, exampleResponse, textToAnalyze string, priorQAs []store.QA, stream bool) (string, error) {
+	systemPrompt := defaultSystemPrompt
+	userContent := "Now, about this following text, " + question + ": " + textToAnalyze
+
+	if mc != nil {
+		systemPrompt = mc.SystemPrompt
+
+		rendered, err := mc.RenderChat(modelconfig.ChatData{
+			Question:        question,
+			ExamplePrompt:   examplePrompt,
+			ExampleResponse: exampleResponse,
+			Text:            textToAnalyze,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		userContent = rendered
+	}
+
+	messages := []backend.Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: examplePrompt},
+		{Role: "assistant", Content: exampleResponse},
+	}
+
+	for _, qa := range priorQAs {
+		messages = append(messages,
+			backend.Message{Role: "user", Content: qa.Question},
+			backend.Message{Role: "assistant", Content: qa.Answer},
+		)
+	}
+
+	messages = append(messages, backend.Message{Role: "user", Content: userContent})
+
+	opts := backend.ChatOptions{Model: bc.Model, Temperature: bc.TemperatureOrDefault()}
+
+	if stream {
+		return b.ChatStream(context.Background(), messages, opts, func(delta string) {
+			fmt.Print(delta)
+		})
+	}
+
+	return b.Chat(context.Background(), messages, opts)
+}
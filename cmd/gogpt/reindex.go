@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/embeddings"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// runReindex backfills embeddings for every question stored before RAG
+// indexing was added, or for any question whose embedding was lost.
+func runReindex() {
+	configDir, err := config.Dir()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	cfg, err := config.Load(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	db, err := store.Open(filepath.Join(configDir, "qa.db"))
+	if err != nil {
+		log.Fatalf("Error opening BoltDB: %v", err)
+	}
+	defer db.Close()
+
+	embedClient := embeddings.NewClient(cfg.APIKey, "")
+	embedModel := cfg.EmbeddingModelOrDefault()
+	ctx := context.Background()
+
+	var qas []store.QA
+
+	if err := store.ForEachQA(db, func(qa store.QA) error {
+		qas = append(qas, qa)
+		return nil
+	}); err != nil {
+		log.Fatalf("Error listing stored questions: %v", err)
+	}
+
+	indexed := 0
+
+	for _, qa := range qas {
+		has, err := store.HasEmbedding(db, qa.Question)
+		if err != nil {
+			log.Fatalf("Error checking embedding for %q: %v", qa.Question, err)
+		}
+
+		if has {
+			continue
+		}
+
+		vector, err := embedClient.Embed(ctx, embedModel, qa.Question)
+		if err != nil {
+			log.Fatalf("Error embedding %q: %v", qa.Question, err)
+		}
+
+		if err := store.StoreEmbedding(db, qa.Question, vector); err != nil {
+			log.Fatalf("Error storing embedding for %q: %v", qa.Question, err)
+		}
+
+		indexed++
+	}
+
+	fmt.Printf("Reindexed %d question(s)\n", indexed)
+}
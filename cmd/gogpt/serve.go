@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/modelconfig"
+	"github.com/jempe/gogpt/internal/server"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// runServe starts "gogpt serve", an OpenAI-compatible HTTP proxy in
+// front of the same backend/model machinery the CLI uses.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	serveBackendName := fs.String("backend", "openai", "Backend to use: openai or local")
+	fs.Parse(args)
+
+	configDir, err := config.Dir()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	cfg, err := config.Load(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	b, bc, err := newBackend(cfg, *serveBackendName)
+	if err != nil {
+		log.Fatalf("Error creating backend: %v", err)
+	}
+
+	db, err := store.Open(filepath.Join(configDir, "qa.db"))
+	if err != nil {
+		log.Fatalf("Error opening BoltDB: %v", err)
+	}
+	defer db.Close()
+
+	modelsDir := filepath.Join(configDir, "models")
+	if err := os.MkdirAll(modelsDir, 0755); err != nil {
+		log.Fatalf("Error creating models directory: %v", err)
+	}
+
+	modelLoader := modelconfig.NewLoader(modelsDir)
+	if err := modelLoader.LoadAll(); err != nil {
+		log.Fatalf("Error loading model configs: %v", err)
+	}
+
+	stopWatch, err := modelLoader.Watch(func(err error) {
+		log.Printf("Error reloading model configs: %v", err)
+	})
+	if err != nil {
+		log.Fatalf("Error watching model configs: %v", err)
+	}
+	defer stopWatch()
+
+	srv := server.New(b, bc, db, cfg.ServeAuthToken, modelLoader)
+
+	log.Printf("Listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, srv.Handler()); err != nil {
+		log.Fatalf("Error serving: %v", err)
+	}
+}
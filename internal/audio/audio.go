@@ -0,0 +1,203 @@
+// Package audio lets gogpt ask questions about audio recordings and hear
+// its answers read back, via OpenAI's whisper transcription and
+// text-to-speech APIs.
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultTranscriptionsURL is the OpenAI whisper transcription endpoint.
+const DefaultTranscriptionsURL = "https://api.openai.com/v1/audio/transcriptions"
+
+// DefaultSpeechURL is the OpenAI text-to-speech endpoint.
+const DefaultSpeechURL = "https://api.openai.com/v1/audio/speech"
+
+// DefaultTranscriptionModel is the whisper model every Transcribe call uses.
+const DefaultTranscriptionModel = "whisper-1"
+
+// DefaultSpeechModel is the TTS model every Speech call uses.
+const DefaultSpeechModel = "tts-1"
+
+// DefaultVoice is the TTS voice used unless overridden.
+const DefaultVoice = "alloy"
+
+// DefaultFormat is the TTS output audio format used unless overridden.
+const DefaultFormat = "mp3"
+
+// audioExtensions are the file extensions routed through transcription
+// instead of being read as plain text.
+var audioExtensions = map[string]bool{
+	".mp3": true,
+	".wav": true,
+	".m4a": true,
+	".ogg": true,
+}
+
+// IsAudioFile reports whether path's extension is a recognized audio
+// format.
+func IsAudioFile(path string) bool {
+	return audioExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Client talks to OpenAI's audio transcription and speech APIs.
+type Client struct {
+	APIKey            string
+	TranscriptionsURL string
+	SpeechURL         string
+
+	client *http.Client
+}
+
+// NewClient creates an audio client. Empty URLs fall back to their
+// defaults.
+func NewClient(apiKey, transcriptionsURL, speechURL string) *Client {
+	if transcriptionsURL == "" {
+		transcriptionsURL = DefaultTranscriptionsURL
+	}
+	if speechURL == "" {
+		speechURL = DefaultSpeechURL
+	}
+
+	return &Client{
+		APIKey:            apiKey,
+		TranscriptionsURL: transcriptionsURL,
+		SpeechURL:         speechURL,
+		client:            &http.Client{},
+	}
+}
+
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// Transcribe uploads the audio file at path to whisper-1 and returns the
+// transcript.
+func (c *Client) Transcribe(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	if err := w.WriteField("model", DefaultTranscriptionModel); err != nil {
+		return "", err
+	}
+
+	part, err := w.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(part, f); err != nil {
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TranscriptionsURL, &buf)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("transcription request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var transcription transcriptionResponse
+	if err := json.Unmarshal(respBody, &transcription); err != nil {
+		return "", err
+	}
+
+	return transcription.Text, nil
+}
+
+type speechRequest struct {
+	Model  string `json:"model"`
+	Input  string `json:"input"`
+	Voice  string `json:"voice"`
+	Format string `json:"response_format"`
+}
+
+// Speech converts text to speech and returns the raw audio bytes in
+// format (e.g. "mp3"), spoken by voice.
+func (c *Client) Speech(ctx context.Context, text, voice, format string) ([]byte, error) {
+	if voice == "" {
+		voice = DefaultVoice
+	}
+	if format == "" {
+		format = DefaultFormat
+	}
+
+	reqBody, err := json.Marshal(speechRequest{
+		Model:  DefaultSpeechModel,
+		Input:  text,
+		Voice:  voice,
+		Format: format,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.SpeechURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("speech request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// SpeechOutputPath returns the path for audio made by Speech: inputPath with
+// its extension replaced by ".speech.<format>".
+func SpeechOutputPath(inputPath, format string) string {
+	ext := filepath.Ext(inputPath)
+	base := strings.TrimSuffix(inputPath, ext)
+	return fmt.Sprintf("%s.speech.%s", base, format)
+}
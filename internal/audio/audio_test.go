@@ -0,0 +1,127 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing or wrong Authorization header: %q", r.Header.Get("Authorization"))
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm: %v", err)
+		}
+
+		if got := r.FormValue("model"); got != DefaultTranscriptionModel {
+			t.Errorf("model = %q, want %q", got, DefaultTranscriptionModel)
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		defer file.Close()
+
+		json.NewEncoder(w).Encode(transcriptionResponse{Text: "hello from the recording"})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "memo.mp3")
+	if err := ioutil.WriteFile(audioPath, []byte("fake audio bytes"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := NewClient("test-key", server.URL, "")
+
+	text, err := c.Transcribe(context.Background(), audioPath)
+	if err != nil {
+		t.Fatalf("Transcribe: %v", err)
+	}
+
+	if want := "hello from the recording"; text != want {
+		t.Errorf("Transcribe() = %q, want %q", text, want)
+	}
+}
+
+func TestTranscribeErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "memo.wav")
+	if err := ioutil.WriteFile(audioPath, []byte("fake audio bytes"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := NewClient("bad-key", server.URL, "")
+
+	if _, err := c.Transcribe(context.Background(), audioPath); err == nil {
+		t.Fatal("Transcribe() error = nil, want error for 401 response")
+	}
+}
+
+func TestSpeech(t *testing.T) {
+	const fakeAudio = "fake mp3 bytes"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req speechRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		if req.Voice != "alloy" || req.Format != "mp3" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		w.Write([]byte(fakeAudio))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", "", server.URL)
+
+	data, err := c.Speech(context.Background(), "hello", "", "")
+	if err != nil {
+		t.Fatalf("Speech: %v", err)
+	}
+
+	if string(data) != fakeAudio {
+		t.Errorf("Speech() = %q, want %q", data, fakeAudio)
+	}
+}
+
+func TestIsAudioFile(t *testing.T) {
+	cases := map[string]bool{
+		"recording.mp3": true,
+		"recording.WAV": true,
+		"notes.m4a":     true,
+		"voice.ogg":     true,
+		"book.txt":      false,
+		"novel.pdf":     false,
+	}
+
+	for path, want := range cases {
+		if got := IsAudioFile(path); got != want {
+			t.Errorf("IsAudioFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestSpeechOutputPath(t *testing.T) {
+	got := SpeechOutputPath("/tmp/question.mp3", "mp3")
+	want := "/tmp/question.speech.mp3"
+	if got != want {
+		t.Errorf("SpeechOutputPath() = %q, want %q", got, want)
+	}
+}
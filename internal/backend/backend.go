@@ -0,0 +1,30 @@
+// Package backend defines the interface that every chat backend (OpenAI,
+// local llama.cpp/ollama-compatible servers, ...) must implement so the
+// rest of gogpt can stay agnostic of where answers actually come from.
+package backend
+
+import "context"
+
+// Message is a single turn in a chat conversation.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatOptions carries the per-request knobs that are usually sourced from
+// a model's config file.
+type ChatOptions struct {
+	Model       string
+	Temperature float64
+}
+
+// Backend answers a chat conversation and returns the assistant's reply.
+type Backend interface {
+	// Chat sends messages and returns the full assistant reply.
+	Chat(ctx context.Context, messages []Message, opts ChatOptions) (string, error)
+
+	// ChatStream behaves like Chat but invokes onDelta with each chunk of
+	// the answer as it becomes available. It still returns the full,
+	// accumulated answer once the stream ends.
+	ChatStream(ctx context.Context, messages []Message, opts ChatOptions, onDelta func(string)) (string, error)
+}
@@ -0,0 +1,112 @@
+// Package local implements the backend.Backend interface against a local
+// llama.cpp/ollama-compatible HTTP server, so users can answer questions
+// with an offline model instead of OpenAI.
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jempe/gogpt/internal/backend"
+)
+
+// DefaultURL is the conventional local chat completions endpoint exposed
+// by both llama.cpp's server and ollama's OpenAI-compatible API.
+const DefaultURL = "http://localhost:11434/v1/chat/completions"
+
+// Backend talks to a local, OpenAI-shaped chat completions endpoint.
+type Backend struct {
+	URL string
+
+	client *http.Client
+}
+
+// New creates a local backend. If url is empty, DefaultURL is used.
+func New(url string) *Backend {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Backend{URL: url, client: &http.Client{}}
+}
+
+type chatCompletionRequest struct {
+	Model       string            `json:"model"`
+	Messages    []backend.Message `json:"messages"`
+	Temperature float64           `json:"temperature"`
+}
+
+type chatCompletionResponse struct {
+	Choices []choice `json:"choices"`
+}
+
+type choice struct {
+	Message backend.Message `json:"message"`
+}
+
+// Chat sends messages to the local server and returns the assistant's
+// reply.
+func (b *Backend) Chat(ctx context.Context, messages []backend.Message, opts backend.ChatOptions) (string, error) {
+	chatReq := chatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    messages,
+		Temperature: opts.Temperature,
+	}
+
+	reqBody, err := json.Marshal(chatReq)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.URL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("local backend request failed with status: %s", resp.Status)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var chatResp chatCompletionResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("local backend returned no choices")
+	}
+
+	return strings.TrimSpace(chatResp.Choices[0].Message.Content), nil
+}
+
+// ChatStream falls back to a single Chat call and delivers the whole
+// answer as one delta; the local server's own streaming format is not
+// wired up yet.
+func (b *Backend) ChatStream(ctx context.Context, messages []backend.Message, opts backend.ChatOptions, onDelta func(string)) (string, error) {
+	answer, err := b.Chat(ctx, messages, opts)
+	if err != nil {
+		return "", err
+	}
+
+	onDelta(answer)
+
+	return answer, nil
+}
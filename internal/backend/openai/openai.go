@@ -0,0 +1,197 @@
+// Package openai implements the backend.Backend interface against the
+// OpenAI chat completions API.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jempe/gogpt/internal/backend"
+)
+
+// DefaultURL is the OpenAI chat completions endpoint.
+const DefaultURL = "https://api.openai.com/v1/chat/completions"
+
+// Backend talks to the OpenAI HTTP API.
+type Backend struct {
+	APIKey string
+	URL    string
+
+	client *http.Client
+}
+
+// New creates an OpenAI backend. If url is empty, DefaultURL is used.
+func New(apiKey, url string) *Backend {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Backend{APIKey: apiKey, URL: url, client: &http.Client{}}
+}
+
+type chatCompletionRequest struct {
+	Model       string            `json:"model"`
+	Messages    []backend.Message `json:"messages"`
+	Temperature float64           `json:"temperature"`
+	Stream      bool              `json:"stream,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`
+	Model   string   `json:"model"`
+	Choices []choice `json:"choices"`
+}
+
+type choice struct {
+	Message      backend.Message `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+}
+
+// Chat sends messages to OpenAI and returns the assistant's reply.
+func (b *Backend) Chat(ctx context.Context, messages []backend.Message, opts backend.ChatOptions) (string, error) {
+	chatReq := chatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    messages,
+		Temperature: opts.Temperature,
+	}
+
+	reqBody, err := json.Marshal(chatReq)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.URL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", b.APIKey))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OpenAI API request failed with status: %s", resp.Status)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var chatResp chatCompletionResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("OpenAI API returned no choices")
+	}
+
+	return strings.TrimSpace(chatResp.Choices[0].Message.Content), nil
+}
+
+type streamChunk struct {
+	Choices []streamChoice `json:"choices"`
+	Error   *apiError      `json:"error"`
+}
+
+type streamChoice struct {
+	Delta        backend.Message `json:"delta"`
+	FinishReason string          `json:"finish_reason"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+}
+
+// doneSentinel is the final "data:" payload OpenAI sends to close a
+// stream.
+const doneSentinel = "[DONE]"
+
+// ChatStream sends messages to OpenAI with streaming enabled, parses the
+// text/event-stream "data: {...}" chunks, and invokes onDelta with each
+// token as it arrives. It returns the full, accumulated answer once the
+// stream ends.
+func (b *Backend) ChatStream(ctx context.Context, messages []backend.Message, opts backend.ChatOptions, onDelta func(string)) (string, error) {
+	chatReq := chatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    messages,
+		Temperature: opts.Temperature,
+		Stream:      true,
+	}
+
+	reqBody, err := json.Marshal(chatReq)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.URL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", b.APIKey))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OpenAI API request failed with status: %s", resp.Status)
+	}
+
+	var full strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			// Blank lines and non-"data:" fields are keepalives/comments.
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == doneSentinel {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return full.String(), err
+		}
+
+		if chunk.Error != nil {
+			return full.String(), fmt.Errorf("OpenAI stream error: %s", chunk.Error.Message)
+		}
+
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		full.WriteString(delta)
+		onDelta(delta)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return full.String(), err
+	}
+
+	return full.String(), nil
+}
@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jempe/gogpt/internal/backend"
+)
+
+func TestChatStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantAnswer string
+		wantErr    bool
+	}{
+		{
+			name: "keepalive comments and blank lines are ignored",
+			body: ": keepalive\n\n" +
+				"data: " + `{"choices":[{"delta":{"content":"Hel"}}]}` + "\n\n" +
+				"\n" +
+				"data: " + `{"choices":[{"delta":{"content":"lo"}}]}` + "\n\n" +
+				"data: [DONE]\n\n",
+			wantAnswer: "Hello",
+		},
+		{
+			name: "DONE sentinel stops the scan before trailing data",
+			body: "data: " + `{"choices":[{"delta":{"content":"Hi"}}]}` + "\n\n" +
+				"data: [DONE]\n\n" +
+				"data: " + `{"choices":[{"delta":{"content":"ignored"}}]}` + "\n\n",
+			wantAnswer: "Hi",
+		},
+		{
+			name: "mid-stream error object stops with an error",
+			body: "data: " + `{"choices":[{"delta":{"content":"Hi"}}]}` + "\n\n" +
+				"data: " + `{"error":{"message":"rate limited"}}` + "\n\n",
+			wantAnswer: "Hi",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			b := New("test-key", server.URL)
+
+			var deltas strings.Builder
+			answer, err := b.ChatStream(context.Background(), []backend.Message{{Role: "user", Content: "hi"}}, backend.ChatOptions{}, func(delta string) {
+				deltas.WriteString(delta)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChatStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if answer != tt.wantAnswer {
+				t.Errorf("ChatStream() answer = %q, want %q", answer, tt.wantAnswer)
+			}
+
+			if deltas.String() != tt.wantAnswer {
+				t.Errorf("onDelta accumulated = %q, want %q", deltas.String(), tt.wantAnswer)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package config loads gogpt's on-disk configuration: the top-level
+// config.json with the OpenAI API key plus a named set of backend
+// configurations selected at runtime with -backend.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jempe/gogpt/internal/embeddings"
+)
+
+// BackendConfig describes how to reach and drive a single backend.
+type BackendConfig struct {
+	Type  string `json:"type"` // "openai" or "local"
+	Model string `json:"model"`
+	// Temperature is a pointer so an explicit "temperature": 0 (deterministic
+	// decoding) can be told apart from the field being omitted entirely.
+	// Use TemperatureOrDefault to resolve it for a request.
+	Temperature *float64 `json:"temperature,omitempty"`
+	Endpoint    string   `json:"endpoint,omitempty"`
+}
+
+// TemperatureOrDefault returns the configured temperature, or
+// DefaultTemperature if none was set.
+func (bc BackendConfig) TemperatureOrDefault() float64 {
+	if bc.Temperature != nil {
+		return *bc.Temperature
+	}
+
+	return DefaultTemperature
+}
+
+// Config is the shape of ~/.gogpt/config.json.
+type Config struct {
+	APIKey         string                   `json:"api_key"`
+	Backends       map[string]BackendConfig `json:"backends"`
+	EmbeddingModel string                   `json:"embedding_model,omitempty"`
+	ServeAuthToken string                   `json:"serve_auth_token,omitempty"`
+}
+
+// DefaultTemperature is used when a backend config omits one.
+const DefaultTemperature = 0.7
+
+// EmbeddingModelOrDefault returns the configured embedding model, falling
+// back to embeddings.DefaultModel.
+func (c *Config) EmbeddingModelOrDefault() string {
+	if c.EmbeddingModel != "" {
+		return c.EmbeddingModel
+	}
+
+	return embeddings.DefaultModel
+}
+
+// Load reads and parses the config file at path.
+func Load(path string) (*Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("config file %s does not exist", path)
+	}
+
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshalling config file: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Backend returns the named backend config, falling back to a bare-bones
+// one if the user never configured it explicitly. Temperature is left
+// unset rather than defaulted here; use TemperatureOrDefault to resolve
+// it for a request.
+func (c *Config) Backend(name string) BackendConfig {
+	if bc, ok := c.Backends[name]; ok {
+		return bc
+	}
+
+	return BackendConfig{Type: name}
+}
+
+// Dir returns ~/.gogpt, creating it if necessary.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".gogpt")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	return dir, nil
+}
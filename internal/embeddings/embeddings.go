@@ -0,0 +1,88 @@
+// Package embeddings is a small client for OpenAI's /v1/embeddings API.
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// DefaultURL is the OpenAI embeddings endpoint.
+const DefaultURL = "https://api.openai.com/v1/embeddings"
+
+// DefaultModel is used when the user hasn't configured one.
+const DefaultModel = "text-embedding-3-small"
+
+// Client embeds text via the OpenAI API.
+type Client struct {
+	APIKey string
+	URL    string
+
+	client *http.Client
+}
+
+// NewClient creates an embeddings client. If url is empty, DefaultURL is
+// used.
+func NewClient(apiKey, url string) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Client{APIKey: apiKey, URL: url, client: &http.Client{}}
+}
+
+type embeddingsRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns the embedding vector for text using model.
+func (c *Client) Embed(ctx context.Context, model, text string) ([]float64, error) {
+	reqBody, err := json.Marshal(embeddingsRequest{Model: model, Input: text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI embeddings request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var embResp embeddingsResponse
+	if err := json.Unmarshal(respBody, &embResp); err != nil {
+		return nil, err
+	}
+
+	if len(embResp.Data) == 0 {
+		return nil, fmt.Errorf("OpenAI embeddings API returned no data")
+	}
+
+	return embResp.Data[0].Embedding, nil
+}
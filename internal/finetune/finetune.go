@@ -0,0 +1,303 @@
+// Package finetune wraps OpenAI's fine-tuning API: uploading training
+// data and creating, listing, inspecting, cancelling and watching
+// fine-tuning jobs.
+package finetune
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultURL is the OpenAI API base URL fine-tuning requests are made
+// against.
+const DefaultURL = "https://api.openai.com/v1"
+
+// Client talks to OpenAI's /v1/files and /v1/fine_tuning/jobs APIs.
+type Client struct {
+	APIKey string
+	URL    string
+
+	client *http.Client
+}
+
+// NewClient creates a fine-tuning client. If url is empty, DefaultURL is
+// used.
+func NewClient(apiKey, url string) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Client{APIKey: apiKey, URL: url, client: &http.Client{}}
+}
+
+// Job is a fine-tuning job as returned by the OpenAI API.
+type Job struct {
+	ID             string `json:"id"`
+	Model          string `json:"model"`
+	Status         string `json:"status"`
+	FineTunedModel string `json:"fine_tuned_model"`
+	TrainingFile   string `json:"training_file"`
+	ValidationFile string `json:"validation_file,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+// Hyperparameters are the tunable knobs for a fine-tuning job. A field is
+// left zero/nil to let OpenAI pick it automatically.
+type Hyperparameters struct {
+	NEpochs                interface{} `json:"n_epochs,omitempty"`
+	BatchSize              interface{} `json:"batch_size,omitempty"`
+	LearningRateMultiplier interface{} `json:"learning_rate_multiplier,omitempty"`
+}
+
+// CreateJobParams are the inputs to CreateJob.
+type CreateJobParams struct {
+	TrainingFile    string
+	ValidationFile  string
+	Model           string
+	Hyperparameters Hyperparameters
+}
+
+// Event is a single fine-tuning job log line.
+type Event struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+}
+
+type listJobsResponse struct {
+	Data []Job `json:"data"`
+}
+
+type listEventsResponse struct {
+	Data []Event `json:"data"`
+}
+
+// UploadFile uploads the local file at path for the given purpose (e.g.
+// "fine-tune") and returns OpenAI's file ID.
+func (c *Client) UploadFile(ctx context.Context, path, purpose string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	if err := w.WriteField("purpose", purpose); err != nil {
+		return "", err
+	}
+
+	part, err := w.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(part, f); err != nil {
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL+"/files", &buf)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("file upload failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &file); err != nil {
+		return "", err
+	}
+
+	return file.ID, nil
+}
+
+// CreateJob starts a new fine-tuning job.
+func (c *Client) CreateJob(ctx context.Context, params CreateJobParams) (*Job, error) {
+	body := map[string]interface{}{
+		"training_file": params.TrainingFile,
+		"model":         params.Model,
+	}
+
+	if params.ValidationFile != "" {
+		body["validation_file"] = params.ValidationFile
+	}
+
+	if params.Hyperparameters.NEpochs != nil || params.Hyperparameters.BatchSize != nil || params.Hyperparameters.LearningRateMultiplier != nil {
+		body["hyperparameters"] = params.Hyperparameters
+	}
+
+	var job Job
+	if err := c.do(ctx, "POST", "/fine_tuning/jobs", body, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ListJobs lists all fine-tuning jobs.
+func (c *Client) ListJobs(ctx context.Context) ([]Job, error) {
+	var list listJobsResponse
+	if err := c.do(ctx, "GET", "/fine_tuning/jobs", nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Data, nil
+}
+
+// GetJob fetches a single fine-tuning job by ID.
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, "GET", "/fine_tuning/jobs/"+id, nil, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CancelJob cancels a running fine-tuning job.
+func (c *Client) CancelJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, "POST", "/fine_tuning/jobs/"+id+"/cancel", nil, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ListEvents lists a fine-tuning job's events, newest first (as returned
+// by the API).
+func (c *Client) ListEvents(ctx context.Context, id string) ([]Event, error) {
+	var list listEventsResponse
+	if err := c.do(ctx, "GET", "/fine_tuning/jobs/"+id+"/events", nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Data, nil
+}
+
+// isTerminal reports whether a fine-tuning job status is final.
+func isTerminal(status string) bool {
+	switch status {
+	case "succeeded", "failed", "cancelled":
+		return true
+	default:
+		return false
+	}
+}
+
+// WatchEvents polls ListEvents and GetJob every interval, invoking
+// onEvent for each event not seen before, until the job reaches a
+// terminal status.
+func (c *Client) WatchEvents(ctx context.Context, id string, interval time.Duration, onEvent func(Event)) (*Job, error) {
+	seen := map[string]bool{}
+
+	for {
+		events, err := c.ListEvents(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(events) - 1; i >= 0; i-- {
+			e := events[i]
+			if seen[e.ID] {
+				continue
+			}
+
+			seen[e.ID] = true
+			onEvent(e)
+		}
+
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTerminal(job.Status) {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fine-tuning API request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	if out != nil {
+		return json.Unmarshal(respBody, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package modelconfig loads per-model YAML config files from
+// ~/.gogpt/models, each pairing a model's backend settings with a Go
+// text/template file for rendering chat prompts. This lets users define
+// distinct assistants (e.g. "book-analyzer", "code-reviewer") without
+// recompiling gogpt.
+package modelconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelConfig is a single assistant definition.
+type ModelConfig struct {
+	Name    string `yaml:"name"`
+	Backend string `yaml:"backend"`
+	ModelID string `yaml:"model_id"`
+	// Temperature is a pointer so an explicit "temperature: 0" (deterministic
+	// decoding) can be told apart from the field being omitted entirely.
+	Temperature  *float64 `yaml:"temperature"`
+	SystemPrompt string   `yaml:"system_prompt"`
+
+	ChatTemplateFile string `yaml:"chat_template"`
+
+	ChatTemplate *template.Template `yaml:"-"`
+}
+
+// ChatData is made available to a model's chat.tmpl.
+type ChatData struct {
+	Question        string
+	ExamplePrompt   string
+	ExampleResponse string
+	Text            string
+}
+
+// RenderChat renders the model's chat template with data.
+func (mc *ModelConfig) RenderChat(data ChatData) (string, error) {
+	if mc.ChatTemplate == nil {
+		return "", fmt.Errorf("model %q has no chat template", mc.Name)
+	}
+
+	var buf bytes.Buffer
+	if err := mc.ChatTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Loader loads and validates model config files from a directory, and can
+// watch it for changes so new assistants are picked up without a restart.
+type Loader struct {
+	Dir string
+
+	mu      sync.RWMutex
+	configs map[string]*ModelConfig
+}
+
+// NewLoader creates a Loader for the given models directory.
+func NewLoader(dir string) *Loader {
+	return &Loader{Dir: dir, configs: map[string]*ModelConfig{}}
+}
+
+// LoadAll (re)loads every *.yaml file in Dir, parsing and validating its
+// templates. A single malformed file fails the whole load so a typo can't
+// silently leave gogpt running on a stale config set.
+func (l *Loader) LoadAll() error {
+	matches, err := filepath.Glob(filepath.Join(l.Dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	configs := make(map[string]*ModelConfig, len(matches))
+
+	for _, path := range matches {
+		mc, err := l.loadFile(path)
+		if err != nil {
+			return fmt.Errorf("error loading model config %s: %w", path, err)
+		}
+
+		configs[mc.Name] = mc
+	}
+
+	l.mu.Lock()
+	l.configs = configs
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Get returns the named model config, if loaded.
+func (l *Loader) Get(name string) (*ModelConfig, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	mc, ok := l.configs[name]
+	return mc, ok
+}
+
+// List returns every currently loaded model config.
+func (l *Loader) List() []*ModelConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	configs := make([]*ModelConfig, 0, len(l.configs))
+	for _, mc := range l.configs {
+		configs = append(configs, mc)
+	}
+
+	return configs
+}
+
+// Watch starts watching Dir for changes and reloads all model configs on
+// every write, create, remove or rename. Reload errors are reported to
+// onError rather than crashing the process, since a broken model file
+// shouldn't take down an assistant that's already running. It returns a
+// function to stop watching.
+func (l *Loader) Watch(onError func(error)) (func() error, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(l.Dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+					if err := l.LoadAll(); err != nil && onError != nil {
+						onError(err)
+					}
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+
+	return watcher.Close, nil
+}
+
+func (l *Loader) loadFile(path string) (*ModelConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mc ModelConfig
+	if err := yaml.Unmarshal(data, &mc); err != nil {
+		return nil, err
+	}
+
+	if mc.Name == "" {
+		mc.Name = strings.TrimSuffix(filepath.Base(path), ".yaml")
+	}
+
+	dir := filepath.Dir(path)
+
+	chatFile := mc.ChatTemplateFile
+	if chatFile == "" {
+		chatFile = mc.Name + ".chat.tmpl"
+	}
+
+	mc.ChatTemplate, err = parseTemplateFile(filepath.Join(dir, chatFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return &mc, nil
+}
+
+func parseTemplateFile(path string) (*template.Template, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.New(filepath.Base(path)).Parse(string(data))
+}
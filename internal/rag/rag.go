@@ -0,0 +1,112 @@
+// Package rag retrieves prior question/answer pairs relevant to a new
+// question, so they can be injected as conversation history before
+// asking a backend.
+//
+// BoltDB has no vector index, so Retrieve does a brute-force scan over
+// every stored embedding on each call: O(N) in the number of previously
+// embedded questions. This is fine for the personal-history sizes gogpt
+// is built for; a large history will need an actual index.
+package rag
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/boltdb/bolt"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// EmbedFunc embeds a single piece of text.
+type EmbedFunc func(ctx context.Context, text string) ([]float64, error)
+
+// Retriever ranks stored question/answer pairs by embedding similarity
+// to a new question.
+type Retriever struct {
+	db    *bolt.DB
+	embed EmbedFunc
+}
+
+// NewRetriever creates a Retriever backed by db, using embed to embed
+// new queries.
+func NewRetriever(db *bolt.DB, embed EmbedFunc) *Retriever {
+	return &Retriever{db: db, embed: embed}
+}
+
+type scoredQA struct {
+	qa    store.QA
+	score float64
+}
+
+// Retrieve embeds query, scans every stored embedding, and returns the
+// top-k prior Q&As whose cosine similarity to query is at least
+// minScore (best match first), along with the embedding it computed for
+// query so callers don't have to re-embed it to store it themselves.
+func (r *Retriever) Retrieve(ctx context.Context, query string, k int, minScore float64) ([]store.QA, []float64, error) {
+	if k <= 0 {
+		return nil, nil, nil
+	}
+
+	queryVec, err := r.embed(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var candidates []scoredQA
+
+	err = store.ForEachEmbedding(r.db, func(hash string, emb store.Embedding) error {
+		score := cosineSimilarity(queryVec, emb.Vector)
+		if score < minScore {
+			return nil
+		}
+
+		candidates = append(candidates, scoredQA{qa: store.QA{Question: emb.Question}, score: score})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+
+	results := make([]store.QA, 0, len(candidates))
+
+	for _, c := range candidates {
+		qa, ok, err := store.GetQA(r.db, c.qa.Question)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ok {
+			results = append(results, *qa)
+		}
+	}
+
+	return results, queryVec, nil
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if
+// either vector has zero magnitude or they differ in length.
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
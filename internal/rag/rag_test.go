@@ -0,0 +1,148 @@
+package rag
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"identical vectors", []float64{1, 2, 3}, []float64{1, 2, 3}, 1},
+		{"opposite vectors", []float64{1, 0}, []float64{-1, 0}, -1},
+		{"orthogonal vectors", []float64{1, 0}, []float64{0, 1}, 0},
+		{"mismatched dimensions", []float64{1, 2, 3}, []float64{1, 2}, 0},
+		{"zero magnitude vector", []float64{0, 0}, []float64{1, 1}, 0},
+		{"empty vectors", []float64{}, []float64{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cosineSimilarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("cosineSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func openTestDB(t *testing.T) *bolt.DB {
+	t.Helper()
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func seed(t *testing.T, db *bolt.DB, question, answer string, vector []float64) {
+	t.Helper()
+
+	if err := store.StoreQA(db, question, answer); err != nil {
+		t.Fatalf("StoreQA(%q): %v", question, err)
+	}
+
+	if err := store.StoreEmbedding(db, question, vector); err != nil {
+		t.Fatalf("StoreEmbedding(%q): %v", question, err)
+	}
+}
+
+func TestRetrieveRanksByScoreAndTruncatesToK(t *testing.T) {
+	db := openTestDB(t)
+
+	seed(t, db, "closest", "closest answer", []float64{1, 0})
+	seed(t, db, "middle", "middle answer", []float64{0.7, 0.7})
+	seed(t, db, "farthest", "farthest answer", []float64{0, 1})
+
+	r := NewRetriever(db, func(ctx context.Context, text string) ([]float64, error) {
+		return []float64{1, 0}, nil
+	})
+
+	results, queryVec, err := r.Retrieve(context.Background(), "query", 2, -1)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2 (k should truncate)", len(results))
+	}
+
+	if results[0].Question != "closest" || results[1].Question != "middle" {
+		t.Errorf("results = %+v, want [closest, middle] best-match-first", results)
+	}
+
+	if len(queryVec) != 2 || queryVec[0] != 1 || queryVec[1] != 0 {
+		t.Errorf("queryVec = %v, want the embedded query vector [1 0]", queryVec)
+	}
+}
+
+func TestRetrieveFiltersByMinScore(t *testing.T) {
+	db := openTestDB(t)
+
+	seed(t, db, "similar", "similar answer", []float64{1, 0})
+	seed(t, db, "dissimilar", "dissimilar answer", []float64{0, 1})
+
+	r := NewRetriever(db, func(ctx context.Context, text string) ([]float64, error) {
+		return []float64{1, 0}, nil
+	})
+
+	results, _, err := r.Retrieve(context.Background(), "query", 5, 0.5)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Question != "similar" {
+		t.Errorf("results = %+v, want only the above-minScore match", results)
+	}
+}
+
+func TestRetrieveKLargerThanCandidateCountReturnsAll(t *testing.T) {
+	db := openTestDB(t)
+
+	seed(t, db, "only", "only answer", []float64{1, 0})
+
+	r := NewRetriever(db, func(ctx context.Context, text string) ([]float64, error) {
+		return []float64{1, 0}, nil
+	})
+
+	results, _, err := r.Retrieve(context.Background(), "query", 10, -1)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Errorf("len(results) = %d, want 1", len(results))
+	}
+}
+
+func TestRetrieveZeroKSkipsEmbeddingAndReturnsNothing(t *testing.T) {
+	db := openTestDB(t)
+
+	called := false
+	r := NewRetriever(db, func(ctx context.Context, text string) ([]float64, error) {
+		called = true
+		return []float64{1, 0}, nil
+	})
+
+	results, vec, err := r.Retrieve(context.Background(), "query", 0, 0)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+
+	if results != nil || vec != nil {
+		t.Errorf("Retrieve(k=0) = %v, %v, want nil, nil", results, vec)
+	}
+
+	if called {
+		t.Error("Retrieve(k=0) embedded the query, want it skipped entirely")
+	}
+}
@@ -0,0 +1,277 @@
+// Package server exposes gogpt's backend/model machinery over an
+// OpenAI-compatible HTTP API, so a local web UI or any OpenAI client can
+// talk to it as a drop-in personal proxy with persistent history.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/jempe/gogpt/internal/backend"
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/modelconfig"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// Server serves /v1/chat/completions and /v1/models against a single
+// backend, logging every request/response pair to the same BoltDB bucket
+// the CLI uses.
+type Server struct {
+	Backend       backend.Backend
+	BackendConfig config.BackendConfig
+	DB            *bolt.DB
+	AuthToken     string
+
+	// ModelConfigs, if set, lets callers select one of the named
+	// assistants from ~/.gogpt/models by passing its name as the
+	// request's "model". Pair it with Loader.Watch so new or edited
+	// assistants take effect without restarting the server.
+	ModelConfigs *modelconfig.Loader
+}
+
+// New creates a Server. AuthToken may be empty to disable bearer-token
+// auth. ModelConfigs may be nil to disable named-assistant selection.
+func New(b backend.Backend, bc config.BackendConfig, db *bolt.DB, authToken string, modelConfigs *modelconfig.Loader) *Server {
+	return &Server{Backend: b, BackendConfig: bc, DB: db, AuthToken: authToken, ModelConfigs: modelConfigs}
+}
+
+// Handler returns the server's http.Handler, wrapped with CORS and
+// bearer-token auth middleware.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/v1/models", s.handleModels)
+
+	return s.withMiddleware(mux)
+}
+
+func (s *Server) withMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if s.AuthToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token != s.AuthToken {
+				http.Error(w, `{"error":"invalid bearer token"}`, http.StatusUnauthorized)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type chatCompletionRequest struct {
+	Model       string            `json:"model"`
+	Messages    []backend.Message `json:"messages"`
+	Temperature float64           `json:"temperature"`
+	Stream      bool              `json:"stream"`
+}
+
+type chatCompletionChoice struct {
+	Index        int             `json:"index"`
+	Message      backend.Message `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+}
+
+type chatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []chatCompletionChoice `json:"choices"`
+}
+
+type chatCompletionChunkChoice struct {
+	Index        int             `json:"index"`
+	Delta        backend.Message `json:"delta"`
+	FinishReason *string         `json:"finish_reason"`
+}
+
+type chatCompletionChunk struct {
+	ID      string                      `json:"id"`
+	Object  string                      `json:"object"`
+	Created int64                       `json:"created"`
+	Model   string                      `json:"model"`
+	Choices []chatCompletionChunkChoice `json:"choices"`
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	model := req.Model
+	if model == "" {
+		model = s.BackendConfig.Model
+	}
+
+	temperature := req.Temperature
+	if temperature == 0 {
+		temperature = s.BackendConfig.TemperatureOrDefault()
+	}
+
+	messages := req.Messages
+
+	if s.ModelConfigs != nil {
+		if mc, ok := s.ModelConfigs.Get(req.Model); ok {
+			if mc.ModelID != "" {
+				model = mc.ModelID
+			}
+			if req.Temperature == 0 && mc.Temperature != nil {
+				temperature = *mc.Temperature
+			}
+			if mc.SystemPrompt != "" {
+				messages = withSystemPrompt(messages, mc.SystemPrompt)
+			}
+		}
+	}
+
+	opts := backend.ChatOptions{Model: model, Temperature: temperature}
+	id := fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+
+	if req.Stream {
+		s.streamChatCompletion(w, r, id, model, opts, messages)
+		return
+	}
+
+	answer, err := s.Backend.Chat(r.Context(), messages, opts)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadGateway)
+		return
+	}
+
+	if err := store.StoreQA(s.DB, lastUserContent(req.Messages), answer); err != nil {
+		log.Printf("error storing request/response: %v", err)
+	}
+
+	resp := chatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []chatCompletionChoice{{
+			Message:      backend.Message{Role: "assistant", Content: answer},
+			FinishReason: "stop",
+		}},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) streamChatCompletion(w http.ResponseWriter, r *http.Request, id, model string, opts backend.ChatOptions, messages []backend.Message) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"error":"streaming not supported"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	answer, err := s.Backend.ChatStream(r.Context(), messages, opts, func(delta string) {
+		chunk := chatCompletionChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   model,
+			Choices: []chatCompletionChunkChoice{{Delta: backend.Message{Content: delta}}},
+		}
+
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	})
+	if err != nil {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+
+		return
+	}
+
+	if err := store.StoreQA(s.DB, lastUserContent(messages), answer); err != nil {
+		log.Printf("error storing request/response: %v", err)
+	}
+
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// withSystemPrompt returns messages with prompt as its leading "system"
+// message, replacing one if the caller already sent one.
+func withSystemPrompt(messages []backend.Message, prompt string) []backend.Message {
+	if len(messages) > 0 && messages[0].Role == "system" {
+		out := make([]backend.Message, len(messages))
+		copy(out, messages)
+		out[0].Content = prompt
+		return out
+	}
+
+	out := make([]backend.Message, 0, len(messages)+1)
+	out = append(out, backend.Message{Role: "system", Content: prompt})
+	out = append(out, messages...)
+	return out
+}
+
+func lastUserContent(messages []backend.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+
+	return ""
+}
+
+type modelObject struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	OwnedBy string `json:"owned_by"`
+}
+
+type modelsResponse struct {
+	Object string        `json:"object"`
+	Data   []modelObject `json:"data"`
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := []modelObject{{ID: s.BackendConfig.Model, Object: "model", OwnedBy: "gogpt"}}
+
+	if s.ModelConfigs != nil {
+		for _, mc := range s.ModelConfigs.List() {
+			data = append(data, modelObject{ID: mc.Name, Object: "model", OwnedBy: "gogpt"})
+		}
+	}
+
+	resp := modelsResponse{Object: "list", Data: data}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/jempe/gogpt/internal/backend"
+	"github.com/jempe/gogpt/internal/config"
+	"github.com/jempe/gogpt/internal/modelconfig"
+	"github.com/jempe/gogpt/internal/store"
+)
+
+// fakeBackend records the messages/opts it was called with and returns a
+// canned answer, optionally split into deltas for ChatStream.
+type fakeBackend struct {
+	answer string
+	deltas []string
+	err    error
+
+	gotMessages []backend.Message
+	gotOpts     backend.ChatOptions
+}
+
+func (f *fakeBackend) Chat(ctx context.Context, messages []backend.Message, opts backend.ChatOptions) (string, error) {
+	f.gotMessages = messages
+	f.gotOpts = opts
+	return f.answer, f.err
+}
+
+func (f *fakeBackend) ChatStream(ctx context.Context, messages []backend.Message, opts backend.ChatOptions, onDelta func(string)) (string, error) {
+	f.gotMessages = messages
+	f.gotOpts = opts
+
+	if f.err != nil {
+		return "", f.err
+	}
+
+	for _, d := range f.deltas {
+		onDelta(d)
+	}
+
+	return f.answer, nil
+}
+
+func openTestDB(t *testing.T) *bolt.DB {
+	t.Helper()
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestHandlerRejectsMissingOrWrongBearerToken(t *testing.T) {
+	b := &fakeBackend{answer: "hi"}
+	srv := New(b, config.BackendConfig{Model: "default-model"}, openTestDB(t), "secret-token", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("missing token: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerAcceptsCorrectBearerToken(t *testing.T) {
+	b := &fakeBackend{answer: "hi"}
+	srv := New(b, config.BackendConfig{Model: "default-model"}, openTestDB(t), "secret-token", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestHandlerAllowsAllRequestsWhenAuthTokenUnset(t *testing.T) {
+	b := &fakeBackend{answer: "hi"}
+	srv := New(b, config.BackendConfig{Model: "default-model"}, openTestDB(t), "", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestChatCompletionsOverridesModelAndPrependsSystemPromptFromModelConfig(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "bookbot.yaml"), "name: bookbot\nmodel_id: gpt-bookbot\nsystem_prompt: \"You are a book analyst.\"\n")
+	mustWriteFile(t, filepath.Join(dir, "bookbot.chat.tmpl"), "{{.Question}}")
+
+	loader := modelconfig.NewLoader(dir)
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+
+	b := &fakeBackend{answer: "the book is about..."}
+	srv := New(b, config.BackendConfig{Model: "default-model"}, openTestDB(t), "", loader)
+
+	body, _ := json.Marshal(chatCompletionRequest{
+		Model:    "bookbot",
+		Messages: []backend.Message{{Role: "user", Content: "what happens in chapter 2?"}},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if b.gotOpts.Model != "gpt-bookbot" {
+		t.Errorf("backend got model = %q, want %q (from model config)", b.gotOpts.Model, "gpt-bookbot")
+	}
+
+	if len(b.gotMessages) == 0 || b.gotMessages[0].Role != "system" || b.gotMessages[0].Content != "You are a book analyst." {
+		t.Errorf("backend got messages = %+v, want a leading system message from the model config", b.gotMessages)
+	}
+}
+
+func TestChatCompletionsStreamFramesEachDeltaAndTerminatesWithDone(t *testing.T) {
+	b := &fakeBackend{answer: "Hello", deltas: []string{"Hel", "lo"}}
+	srv := New(b, config.BackendConfig{Model: "default-model"}, openTestDB(t), "", nil)
+
+	body, _ := json.Marshal(chatCompletionRequest{
+		Model:    "default-model",
+		Stream:   true,
+		Messages: []backend.Message{{Role: "user", Content: "hi"}},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	frames := strings.Split(strings.TrimSuffix(w.Body.String(), "\n\n"), "\n\n")
+	if len(frames) != 3 {
+		t.Fatalf("got %d SSE frames, want 3 (two deltas + [DONE]); body: %q", len(frames), w.Body.String())
+	}
+
+	var gotContent string
+	for _, frame := range frames[:2] {
+		data := strings.TrimPrefix(frame, "data: ")
+
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("unmarshal chunk %q: %v", data, err)
+		}
+
+		if len(chunk.Choices) != 1 {
+			t.Fatalf("chunk %+v has %d choices, want 1", chunk, len(chunk.Choices))
+		}
+
+		gotContent += chunk.Choices[0].Delta.Content
+	}
+
+	if gotContent != "Hello" {
+		t.Errorf("accumulated delta content = %q, want %q", gotContent, "Hello")
+	}
+
+	if frames[2] != "data: [DONE]" {
+		t.Errorf("final frame = %q, want the [DONE] sentinel", frames[2])
+	}
+}
+
+func mustWriteFile(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}
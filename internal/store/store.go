@@ -0,0 +1,179 @@
+// Package store persists gogpt's local state — question/answer pairs,
+// fine-tuning job records, and question embeddings — to a BoltDB file.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/boltdb/bolt"
+)
+
+// BucketName is the bucket holding question/answer pairs.
+const BucketName = "questions_and_answers"
+
+// FinetuneBucketName is the bucket holding created fine-tuning job
+// records.
+const FinetuneBucketName = "finetune_jobs"
+
+// EmbeddingsBucketName is the bucket holding question embeddings, keyed
+// by QuestionHash.
+const EmbeddingsBucketName = "embeddings"
+
+// QA is a single stored question/answer pair.
+type QA struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// FinetuneJob is a locally persisted record of a fine-tuning job created
+// through gogpt.
+type FinetuneJob struct {
+	ID        string `json:"id"`
+	Model     string `json:"model"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// Embedding is a question's embedding vector, along with the question
+// text itself so a retriever can look the matching answer back up in
+// BucketName without a second index.
+type Embedding struct {
+	Question string    `json:"question"`
+	Vector   []float64 `json:"vector"`
+}
+
+// Open opens (creating if needed) the BoltDB file at path and ensures all
+// of gogpt's buckets exist.
+func Open(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range []string{BucketName, FinetuneBucketName, EmbeddingsBucketName} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// StoreQA saves a question/answer pair, keyed by the question itself.
+func StoreQA(db *bolt.DB, question, answer string) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(BucketName))
+		qa := &QA{Question: question, Answer: answer}
+		data, err := json.Marshal(qa)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(question), data)
+	})
+}
+
+// GetQA looks up the stored answer for question, if any.
+func GetQA(db *bolt.DB, question string) (*QA, bool, error) {
+	var qa *QA
+
+	err := db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketName)).Get([]byte(question))
+		if data == nil {
+			return nil
+		}
+
+		qa = &QA{}
+		return json.Unmarshal(data, qa)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return qa, qa != nil, nil
+}
+
+// StoreFinetuneJob saves a fine-tuning job record, keyed by its job ID.
+func StoreFinetuneJob(db *bolt.DB, job FinetuneJob) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(FinetuneBucketName))
+		data, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(job.ID), data)
+	})
+}
+
+// QuestionHash is the embeddings bucket key for a given question.
+func QuestionHash(question string) string {
+	sum := sha256.Sum256([]byte(question))
+	return hex.EncodeToString(sum[:])
+}
+
+// StoreEmbedding saves a question's embedding vector, keyed by
+// QuestionHash(question).
+func StoreEmbedding(db *bolt.DB, question string, vector []float64) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(EmbeddingsBucketName))
+		data, err := json.Marshal(Embedding{Question: question, Vector: vector})
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(QuestionHash(question)), data)
+	})
+}
+
+// HasEmbedding reports whether question already has a stored embedding.
+func HasEmbedding(db *bolt.DB, question string) (bool, error) {
+	found := false
+
+	err := db.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket([]byte(EmbeddingsBucketName)).Get([]byte(QuestionHash(question))) != nil
+		return nil
+	})
+
+	return found, err
+}
+
+// ForEachEmbedding calls fn for every stored embedding. BoltDB has no
+// vector index, so retrieval is a brute-force O(N) scan over this
+// bucket; see internal/rag for the ranking logic built on top of it.
+func ForEachEmbedding(db *bolt.DB, fn func(hash string, emb Embedding) error) error {
+	return db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(EmbeddingsBucketName)).ForEach(func(k, v []byte) error {
+			var emb Embedding
+			if err := json.Unmarshal(v, &emb); err != nil {
+				return err
+			}
+
+			return fn(string(k), emb)
+		})
+	})
+}
+
+// ForEachQA calls fn for every stored question/answer pair.
+func ForEachQA(db *bolt.DB, fn func(qa QA) error) error {
+	return db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).ForEach(func(k, v []byte) error {
+			var qa QA
+			if err := json.Unmarshal(v, &qa); err != nil {
+				return err
+			}
+
+			return fn(qa)
+		})
+	})
+}